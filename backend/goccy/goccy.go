@@ -0,0 +1,28 @@
+// Package goccy registers goccy/go-json as jsonhelper's decoder.
+// Importing this package for its side effect is enough:
+//
+//	import _ "xgdapg/jsonhelper/backend/goccy"
+package goccy
+
+import (
+	"bytes"
+
+	native "github.com/goccy/go-json"
+	"xgdapg/jsonhelper"
+)
+
+type decoder struct{}
+
+// Unmarshal goes through a Decoder with UseNumber enabled, rather than the
+// package-level native.Unmarshal, so decoded numbers arrive as json.Number
+// instead of float64 and jsonhelper's int64/big.Int precision guarantees
+// (see num.go) survive the backend swap.
+func (decoder) Unmarshal(data []byte, v interface{}) error {
+	dec := native.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func init() {
+	jsonhelper.SetDecoder(decoder{})
+}
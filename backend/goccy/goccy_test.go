@@ -0,0 +1,28 @@
+package goccy
+
+import (
+	"testing"
+
+	"xgdapg/jsonhelper"
+)
+
+// TestNumberPrecision pins the fix for the precision bug chunk0-4 closed:
+// registering this backend must not silently downgrade large integers to
+// float64, the same way Parse's own lexer doesn't.
+func TestNumberPrecision(t *testing.T) {
+	n, err := jsonhelper.Parse([]byte(`{"id":9007199254740993}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	id := n.Key("id")
+	if !id.IsInt() {
+		t.Fatalf("id: expected IsInt() to be true")
+	}
+	i64, err := id.ToInt64()
+	if err != nil {
+		t.Fatalf("id.ToInt64: %v", err)
+	}
+	if i64 != 9007199254740993 {
+		t.Fatalf("id.ToInt64 = %d, want 9007199254740993 (lost precision)", i64)
+	}
+}
@@ -0,0 +1,31 @@
+// Package jsoniter registers json-iterator/go as jsonhelper's decoder.
+// Importing this package for its side effect is enough:
+//
+//	import _ "xgdapg/jsonhelper/backend/jsoniter"
+package jsoniter
+
+import (
+	native "github.com/json-iterator/go"
+	"xgdapg/jsonhelper"
+)
+
+// config matches ConfigCompatibleWithStandardLibrary but additionally
+// turns on UseNumber, so decoded numbers arrive as json.Number instead of
+// float64 and jsonhelper's int64/big.Int precision guarantees (see num.go)
+// survive the backend swap.
+var config = native.Config{
+	EscapeHTML:             true,
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+	UseNumber:              true,
+}.Froze()
+
+type decoder struct{}
+
+func (decoder) Unmarshal(data []byte, v interface{}) error {
+	return config.Unmarshal(data, v)
+}
+
+func init() {
+	jsonhelper.SetDecoder(decoder{})
+}
@@ -0,0 +1,41 @@
+package jsonhelper
+
+import "errors"
+
+// Decoder lets an alternative JSON implementation stand in for the
+// package's own lexer. It matches the Unmarshal signature shared by
+// encoding/json, json-iterator/go and goccy/go-json, so any of them can be
+// wired in without touching call sites; see the jsonhelper/backend/*
+// subpackages for ready-made adapters.
+type Decoder interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// activeDecoder is nil by default, meaning Parse uses its own lazy lexer.
+var activeDecoder Decoder
+
+// SetDecoder installs d as the backend Parse uses to decode JSON. Passing
+// nil restores the package's own lexer. Decoding through d is eager: the
+// whole document is unmarshaled up front, trading away Parse's lazy
+// byte-range parsing for whatever throughput d offers.
+func SetDecoder(d Decoder) {
+	activeDecoder = d
+}
+
+func parseWithDecoder(d Decoder, data []byte) (Node, error) {
+	if data[0] == '{' {
+		v := map[string]interface{}{}
+		if err := d.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return createNode(v)
+	}
+	if data[0] == '[' {
+		v := []interface{}{}
+		if err := d.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return createNode(v)
+	}
+	return nil, errors.New("Invalid JSON format")
+}
@@ -0,0 +1,69 @@
+package jsonhelper
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+func (n *nodeMap) Range(fn func(key string, v Node) bool) {
+	for k := range n.v {
+		if !fn(k, n.Key(k)) {
+			return
+		}
+	}
+}
+
+func (n *nodeArray) Range(fn func(key string, v Node) bool) {}
+func (n *nodeValue) Range(fn func(key string, v Node) bool) {}
+func (n *nodeError) Range(fn func(key string, v Node) bool) {}
+
+func (n *nodeArray) Each(fn func(i int, v Node) bool) {
+	for i := range n.v {
+		if !fn(i, n.Index(i)) {
+			return
+		}
+	}
+}
+
+func (n *nodeMap) Each(fn func(i int, v Node) bool)   {}
+func (n *nodeValue) Each(fn func(i int, v Node) bool) {}
+func (n *nodeError) Each(fn func(i int, v Node) bool) {}
+
+func bindNode(n Node, v interface{}) error {
+	b, err := n.Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (n *nodeMap) Bind(v interface{}) error   { return bindNode(n, v) }
+func (n *nodeArray) Bind(v interface{}) error { return bindNode(n, v) }
+func (n *nodeValue) Bind(v interface{}) error { return bindNode(n, v) }
+func (n *nodeError) Bind(v interface{}) error { return n.e }
+
+// As decodes n into a value of type T, the same way json.Unmarshal would,
+// so callers can bridge from the dynamic Node API back into a typed struct
+// at any subtree.
+func As[T any](n Node) (T, error) {
+	var out T
+	err := n.Bind(&out)
+	return out, err
+}
+
+// Slice decodes each element of the array Node n into a T.
+func Slice[T any](n Node) ([]T, error) {
+	if !n.IsArray() {
+		return nil, errors.New("Node is not array")
+	}
+	result := make([]T, n.Len())
+	var err error
+	n.Each(func(i int, v Node) bool {
+		result[i], err = As[T](v)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package jsonhelper
+
+import "testing"
+
+func TestRangeEarlyExit(t *testing.T) {
+	n, err := Parse([]byte(`{"a":1,"b":2,"c":3}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	seen := map[string]bool{}
+	n.Range(func(key string, v Node) bool {
+		seen[key] = true
+		return len(seen) < 2
+	})
+	if len(seen) != 2 {
+		t.Fatalf("Range visited %d keys, want 2 (early exit)", len(seen))
+	}
+}
+
+func TestEachEarlyExit(t *testing.T) {
+	n, err := Parse([]byte(`[10,20,30,40]`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	count := 0
+	n.Each(func(i int, v Node) bool {
+		count++
+		return i < 1
+	})
+	if count != 2 {
+		t.Fatalf("Each visited %d elements, want 2 (early exit)", count)
+	}
+}
+
+type iterPerson struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestAsCleanDoc(t *testing.T) {
+	n, err := Parse([]byte(`{"name":"Ada","age":30}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p, err := As[iterPerson](n)
+	if err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	if p.Name != "Ada" || p.Age != 30 {
+		t.Fatalf("As() = %+v, want {Ada 30}", p)
+	}
+}
+
+func TestAsDocWithUnrelatedNull(t *testing.T) {
+	n, err := Parse([]byte(`{"name":"Ada","age":30,"note":null}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p, err := As[iterPerson](n)
+	if err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	if p.Name != "Ada" || p.Age != 30 {
+		t.Fatalf("As() = %+v, want {Ada 30}", p)
+	}
+}
+
+func TestSliceCleanDoc(t *testing.T) {
+	n, err := Parse([]byte(`[{"name":"Ada","age":30},{"name":"Bo","age":40}]`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	people, err := Slice[iterPerson](n)
+	if err != nil {
+		t.Fatalf("Slice: %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[1].Name != "Bo" {
+		t.Fatalf("Slice() = %+v, want [{Ada 30} {Bo 40}]", people)
+	}
+}
+
+func TestSliceDocWithUnrelatedNull(t *testing.T) {
+	n, err := Parse([]byte(`[{"name":"Ada","age":30,"note":null},{"name":"Bo","age":40}]`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	people, err := Slice[iterPerson](n)
+	if err != nil {
+		t.Fatalf("Slice: %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[1].Name != "Bo" {
+		t.Fatalf("Slice() = %+v, want [{Ada 30} {Bo 40}]", people)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"reflect"
 	"strconv"
 )
@@ -17,45 +18,86 @@ const (
 	tNum
 	tBool
 	tString
+	tNull
 )
 
 type Node interface {
 	Key(k string) Node
 	Index(i int) Node
+	Path(expr string) Node
+	Query(expr string) ([]Node, error)
+	Set(value interface{}, path ...string) (Node, error)
+	SetIndex(value interface{}, i int) (Node, error)
+	Delete(path ...string) error
+	ArrayAppend(value interface{}, path ...string) error
+	ArrayConcat(value interface{}, path ...string) error
+	Merge(other Node) error
+	Bytes() ([]byte, error)
+	String() string
 	IsMap() bool
 	IsArray() bool
 	IsNum() bool
 	IsBool() bool
 	IsString() bool
+	IsNull() bool
 	ToMap() (map[string]Node, error)
 	ToArray() ([]Node, error)
 	ToInt() (int, error)
 	ToInt64() (int64, error)
+	ToUint64() (uint64, error)
 	ToFloat64() (float64, error)
+	ToBigInt() (*big.Int, error)
+	ToBigFloat() (*big.Float, error)
 	ToBool() (bool, error)
 	ToString() (string, error)
+	// IsInt reports whether a num node's text has no fractional part and
+	// no exponent (so 1e2 counts as a float); IsFloat is true for every
+	// other num node. Raw returns the number's original textual form, undistorted by any
+	// float64 round-trip.
+	IsInt() bool
+	IsFloat() bool
+	Raw() string
+	// Range and Each iterate a map/array in place, without allocating the
+	// full slice/map ToMap/ToArray would. The callback stops iteration by
+	// returning false. Calling them on the wrong node kind is a no-op.
+	Range(fn func(key string, v Node) bool)
+	Each(fn func(i int, v Node) bool)
+	// Bind re-marshals this subtree and unmarshals it into v, the same way
+	// json.Unmarshal would, for bridging back into typed structs.
+	Bind(v interface{}) error
+	// Len reports the number of keys (map) or elements (array) without
+	// materializing any child Node. It is 0 for scalars and error nodes.
+	Len() int
 }
 
 func Parse(data []byte) (Node, error) {
 	data = bytes.TrimSpace(data)
-	if data[0] == '{' {
-		v := map[string]interface{}{}
-		if err := json.Unmarshal(data, &v); err != nil {
-			return nil, err
-		}
-		return createNode(v)
+	if len(data) == 0 {
+		return nil, errors.New("Invalid JSON format")
 	}
-	if data[0] == '[' {
-		v := []interface{}{}
-		if err := json.Unmarshal(data, &v); err != nil {
-			return nil, err
-		}
-		return createNode(v)
+	if activeDecoder != nil {
+		return parseWithDecoder(activeDecoder, data)
+	}
+	if data[0] == '{' || data[0] == '[' {
+		return parseContainer(data)
 	}
 	return nil, errors.New("Invalid JSON format")
 }
 
+// createNode wraps a decoded value in the matching Node implementation. A
+// json.RawMessage is a container whose own parse was deferred by the lexer;
+// it is parsed here, the first time something actually visits it.
 func createNode(i interface{}) (Node, error) {
+	if i == nil {
+		return &nodeValue{t: tNull}, nil
+	}
+	if rm, ok := i.(json.RawMessage); ok {
+		return parseContainer(rm)
+	}
+	if num, ok := i.(json.Number); ok {
+		return &nodeValue{t: tNum, v: num}, nil
+	}
+
 	r := reflect.ValueOf(i)
 	switch r.Kind() {
 	case reflect.Map:
@@ -110,11 +152,20 @@ func (n *nodeError) Index(i int) Node {
 	return n
 }
 
+func (n *nodeError) Path(expr string) Node {
+	return n
+}
+
+func (n *nodeError) Query(expr string) ([]Node, error) {
+	return nil, n.e
+}
+
 func (n *nodeError) IsMap() bool    { return false }
 func (n *nodeError) IsArray() bool  { return false }
 func (n *nodeError) IsNum() bool    { return false }
 func (n *nodeError) IsBool() bool   { return false }
 func (n *nodeError) IsString() bool { return false }
+func (n *nodeError) IsNull() bool   { return false }
 
 func (n *nodeError) ToMap() (map[string]Node, error) {
 	return nil, n.e
@@ -144,6 +195,34 @@ func (n *nodeError) ToString() (string, error) {
 	return "", n.e
 }
 
+func (n *nodeError) Len() int {
+	return 0
+}
+
+func (n *nodeError) ToUint64() (uint64, error) {
+	return 0, n.e
+}
+
+func (n *nodeError) ToBigInt() (*big.Int, error) {
+	return nil, n.e
+}
+
+func (n *nodeError) ToBigFloat() (*big.Float, error) {
+	return nil, n.e
+}
+
+func (n *nodeError) IsInt() bool {
+	return false
+}
+
+func (n *nodeError) IsFloat() bool {
+	return false
+}
+
+func (n *nodeError) Raw() string {
+	return ""
+}
+
 //nodeMap
 type nodeMap struct {
 	v map[string]interface{}
@@ -174,6 +253,7 @@ func (n *nodeMap) IsArray() bool  { return false }
 func (n *nodeMap) IsNum() bool    { return false }
 func (n *nodeMap) IsBool() bool   { return false }
 func (n *nodeMap) IsString() bool { return false }
+func (n *nodeMap) IsNull() bool   { return false }
 
 func (n *nodeMap) ToMap() (map[string]Node, error) {
 	result := map[string]Node{}
@@ -207,6 +287,34 @@ func (n *nodeMap) ToString() (string, error) {
 	return "", errors.New("Node is not string")
 }
 
+func (n *nodeMap) Len() int {
+	return len(n.v)
+}
+
+func (n *nodeMap) ToUint64() (uint64, error) {
+	return 0, errors.New("Node is not number")
+}
+
+func (n *nodeMap) ToBigInt() (*big.Int, error) {
+	return nil, errors.New("Node is not number")
+}
+
+func (n *nodeMap) ToBigFloat() (*big.Float, error) {
+	return nil, errors.New("Node is not number")
+}
+
+func (n *nodeMap) IsInt() bool {
+	return false
+}
+
+func (n *nodeMap) IsFloat() bool {
+	return false
+}
+
+func (n *nodeMap) Raw() string {
+	return ""
+}
+
 //nodeArray
 type nodeArray struct {
 	v []interface{}
@@ -237,6 +345,7 @@ func (n *nodeArray) IsArray() bool  { return true }
 func (n *nodeArray) IsNum() bool    { return false }
 func (n *nodeArray) IsBool() bool   { return false }
 func (n *nodeArray) IsString() bool { return false }
+func (n *nodeArray) IsNull() bool   { return false }
 
 func (n *nodeArray) ToMap() (map[string]Node, error) {
 	return nil, errors.New("Node is not map")
@@ -270,6 +379,34 @@ func (n *nodeArray) ToString() (string, error) {
 	return "", errors.New("Node is not string")
 }
 
+func (n *nodeArray) Len() int {
+	return len(n.v)
+}
+
+func (n *nodeArray) ToUint64() (uint64, error) {
+	return 0, errors.New("Node is not number")
+}
+
+func (n *nodeArray) ToBigInt() (*big.Int, error) {
+	return nil, errors.New("Node is not number")
+}
+
+func (n *nodeArray) ToBigFloat() (*big.Float, error) {
+	return nil, errors.New("Node is not number")
+}
+
+func (n *nodeArray) IsInt() bool {
+	return false
+}
+
+func (n *nodeArray) IsFloat() bool {
+	return false
+}
+
+func (n *nodeArray) Raw() string {
+	return ""
+}
+
 //nodeValue
 type nodeValue struct {
 	t nodeType
@@ -289,6 +426,7 @@ func (n *nodeValue) IsArray() bool  { return false }
 func (n *nodeValue) IsNum() bool    { return n.t == tNum }
 func (n *nodeValue) IsBool() bool   { return n.t == tBool }
 func (n *nodeValue) IsString() bool { return n.t == tString }
+func (n *nodeValue) IsNull() bool   { return n.t == tNull }
 
 func (n *nodeValue) ToMap() (map[string]Node, error) {
 	return nil, errors.New("Node is not map")
@@ -298,27 +436,6 @@ func (n *nodeValue) ToArray() ([]Node, error) {
 	return nil, errors.New("Node is not array")
 }
 
-func (n *nodeValue) ToInt() (int, error) {
-	if n.IsNum() {
-		return int(n.v.(float64)), nil
-	}
-	return 0, errors.New("Node is not number")
-}
-
-func (n *nodeValue) ToInt64() (int64, error) {
-	if n.IsNum() {
-		return int64(n.v.(float64)), nil
-	}
-	return 0, errors.New("Node is not number")
-}
-
-func (n *nodeValue) ToFloat64() (float64, error) {
-	if n.IsNum() {
-		return n.v.(float64), nil
-	}
-	return 0, errors.New("Node is not number")
-}
-
 func (n *nodeValue) ToBool() (bool, error) {
 	if n.IsBool() {
 		return n.v.(bool), nil
@@ -332,3 +449,7 @@ func (n *nodeValue) ToString() (string, error) {
 	}
 	return "", errors.New("Node is not string")
 }
+
+func (n *nodeValue) Len() int {
+	return 0
+}
@@ -0,0 +1,338 @@
+package jsonhelper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// jsonpath segment kinds, compiled once from the expression string and then
+// walked against the parsed Node tree.
+type pathSegKind int
+
+const (
+	segChild pathSegKind = iota
+	segIndex
+	segSlice
+	segWildcard
+	segRecursive
+	segFilter
+	segUnionChild
+	segUnionIndex
+)
+
+type pathSeg struct {
+	kind   pathSegKind
+	name   string   // segChild
+	index  int      // segIndex
+	names  []string // segUnionChild
+	idxs   []int    // segUnionIndex
+	filter *filterExpr
+
+	// segSlice
+	start, end, step          int
+	hasStart, hasEnd, hasStep bool
+}
+
+// Path evaluates a JSONPath expression and returns the first matching Node,
+// or a Node that reports an error on no match.
+func (n *nodeMap) Path(expr string) Node   { return evalPath(n, expr) }
+func (n *nodeArray) Path(expr string) Node { return evalPath(n, expr) }
+func (n *nodeValue) Path(expr string) Node { return evalPath(n, expr) }
+
+// Query evaluates a JSONPath expression and returns every matching Node.
+func (n *nodeMap) Query(expr string) ([]Node, error)   { return queryPath(n, expr) }
+func (n *nodeArray) Query(expr string) ([]Node, error) { return queryPath(n, expr) }
+func (n *nodeValue) Query(expr string) ([]Node, error) { return queryPath(n, expr) }
+
+func evalPath(root Node, expr string) Node {
+	nodes, err := queryPath(root, expr)
+	if err != nil {
+		return &nodeError{e: err}
+	}
+	if len(nodes) == 0 {
+		return &nodeError{e: errors.New("jsonpath `" + expr + "` matched nothing")}
+	}
+	return nodes[0]
+}
+
+func queryPath(root Node, expr string) ([]Node, error) {
+	segs, err := compilePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	cur := []Node{root}
+	for _, seg := range segs {
+		cur = applySeg(cur, seg)
+		if len(cur) == 0 {
+			break
+		}
+	}
+	return cur, nil
+}
+
+func applySeg(in []Node, seg pathSeg) []Node {
+	out := []Node{}
+	for _, n := range in {
+		switch seg.kind {
+		case segChild:
+			if n.IsMap() {
+				if v := n.Key(seg.name); v.IsMap() || v.IsArray() || v.IsNum() || v.IsBool() || v.IsString() || v.IsNull() {
+					out = append(out, v)
+				}
+			}
+		case segIndex:
+			if n.IsArray() {
+				if v := n.Index(normalizeIndex(seg.index, n.Len())); v.IsMap() || v.IsArray() || v.IsNum() || v.IsBool() || v.IsString() || v.IsNull() {
+					out = append(out, v)
+				}
+			}
+		case segUnionChild:
+			if n.IsMap() {
+				for _, name := range seg.names {
+					if v := n.Key(name); v.IsMap() || v.IsArray() || v.IsNum() || v.IsBool() || v.IsString() || v.IsNull() {
+						out = append(out, v)
+					}
+				}
+			}
+		case segUnionIndex:
+			if n.IsArray() {
+				for _, idx := range seg.idxs {
+					if v := n.Index(normalizeIndex(idx, n.Len())); v.IsMap() || v.IsArray() || v.IsNum() || v.IsBool() || v.IsString() || v.IsNull() {
+						out = append(out, v)
+					}
+				}
+			}
+		case segSlice:
+			if n.IsArray() {
+				out = append(out, sliceArray(n, seg)...)
+			}
+		case segWildcard:
+			out = append(out, children(n)...)
+		case segRecursive:
+			out = append(out, descendants(n)...)
+		case segFilter:
+			for _, c := range children(n) {
+				if seg.filter.match(c) {
+					out = append(out, c)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func children(n Node) []Node {
+	out := []Node{}
+	if n.IsMap() {
+		m, _ := n.ToMap()
+		for _, v := range m {
+			out = append(out, v)
+		}
+	} else if n.IsArray() {
+		a, _ := n.ToArray()
+		out = append(out, a...)
+	}
+	return out
+}
+
+func descendants(n Node) []Node {
+	out := []Node{n}
+	for _, c := range children(n) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+func sliceArray(n Node, seg pathSeg) []Node {
+	a, _ := n.ToArray()
+	length := len(a)
+	step := 1
+	if seg.hasStep {
+		step = seg.step
+	}
+	if step == 0 {
+		return nil
+	}
+	start, end := 0, length
+	if step > 0 {
+		if seg.hasStart {
+			start = normalizeIndex(seg.start, length)
+		}
+		if seg.hasEnd {
+			end = normalizeIndex(seg.end, length)
+		}
+	} else {
+		start, end = length-1, -1
+		if seg.hasStart {
+			start = normalizeIndex(seg.start, length)
+		}
+		if seg.hasEnd {
+			end = normalizeIndex(seg.end, length)
+		}
+	}
+	out := []Node{}
+	if step > 0 {
+		for i := start; i < end && i < length; i += step {
+			if i >= 0 {
+				out = append(out, a[i])
+			}
+		}
+	} else {
+		for i := start; i > end && i >= 0; i += step {
+			if i < length {
+				out = append(out, a[i])
+			}
+		}
+	}
+	return out
+}
+
+func normalizeIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	return i
+}
+
+// compilePath parses a JSONPath expression into a sequence of segments.
+func compilePath(expr string) ([]pathSeg, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "$") {
+		expr = expr[1:]
+	}
+	segs := []pathSeg{}
+	i := 0
+	for i < len(expr) {
+		switch {
+		case strings.HasPrefix(expr[i:], ".."):
+			segs = append(segs, pathSeg{kind: segRecursive})
+			i += 2
+			if i < len(expr) && expr[i] != '[' {
+				name, n := readName(expr[i:])
+				if name == "" {
+					return nil, errors.New("jsonpath: invalid expression after `..`")
+				}
+				segs = append(segs, pathSeg{kind: segChild, name: name})
+				i += n
+			}
+		case expr[i] == '.':
+			i++
+			if i < len(expr) && expr[i] == '*' {
+				segs = append(segs, pathSeg{kind: segWildcard})
+				i++
+				continue
+			}
+			name, n := readName(expr[i:])
+			if name == "" {
+				return nil, errors.New("jsonpath: expected name after `.`")
+			}
+			segs = append(segs, pathSeg{kind: segChild, name: name})
+			i += n
+		case expr[i] == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, errors.New("jsonpath: unterminated `[`")
+			}
+			inner := expr[i+1 : i+end]
+			seg, err := parseBracket(inner)
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+			i += end + 1
+		default:
+			return nil, errors.New("jsonpath: unexpected character `" + string(expr[i]) + "`")
+		}
+	}
+	return segs, nil
+}
+
+func readName(s string) (string, int) {
+	if len(s) > 0 && s[0] == '\'' {
+		if end := strings.IndexByte(s[1:], '\''); end >= 0 {
+			return s[1 : end+1], end + 2
+		}
+	}
+	n := 0
+	for n < len(s) && s[n] != '.' && s[n] != '[' {
+		n++
+	}
+	return s[:n], n
+}
+
+func parseBracket(inner string) (pathSeg, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return pathSeg{kind: segWildcard}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		f, err := compileFilter(inner[2 : len(inner)-1])
+		if err != nil {
+			return pathSeg{}, err
+		}
+		return pathSeg{kind: segFilter, filter: f}, nil
+	case strings.HasPrefix(inner, "'"):
+		names := []string{}
+		for _, part := range strings.Split(inner, ",") {
+			part = strings.TrimSpace(part)
+			names = append(names, strings.Trim(part, "'"))
+		}
+		if len(names) == 1 {
+			return pathSeg{kind: segChild, name: names[0]}, nil
+		}
+		return pathSeg{kind: segUnionChild, names: names}, nil
+	case strings.Contains(inner, ":"):
+		return parseSlice(inner)
+	default:
+		parts := strings.Split(inner, ",")
+		if len(parts) == 1 {
+			idx, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+			if err != nil {
+				return pathSeg{}, errors.New("jsonpath: invalid index `" + inner + "`")
+			}
+			return pathSeg{kind: segIndex, index: idx}, nil
+		}
+		idxs := []int{}
+		for _, part := range parts {
+			idx, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return pathSeg{}, errors.New("jsonpath: invalid index `" + part + "`")
+			}
+			idxs = append(idxs, idx)
+		}
+		return pathSeg{kind: segUnionIndex, idxs: idxs}, nil
+	}
+}
+
+func parseSlice(inner string) (pathSeg, error) {
+	parts := strings.Split(inner, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return pathSeg{}, errors.New("jsonpath: invalid slice `" + inner + "`")
+	}
+	seg := pathSeg{kind: segSlice}
+	if s := strings.TrimSpace(parts[0]); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return pathSeg{}, errors.New("jsonpath: invalid slice start `" + s + "`")
+		}
+		seg.start, seg.hasStart = v, true
+	}
+	if s := strings.TrimSpace(parts[1]); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return pathSeg{}, errors.New("jsonpath: invalid slice end `" + s + "`")
+		}
+		seg.end, seg.hasEnd = v, true
+	}
+	if len(parts) == 3 {
+		if s := strings.TrimSpace(parts[2]); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return pathSeg{}, errors.New("jsonpath: invalid slice step `" + s + "`")
+			}
+			seg.step, seg.hasStep = v, true
+		}
+	}
+	return seg, nil
+}
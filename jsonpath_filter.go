@@ -0,0 +1,232 @@
+package jsonhelper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// filterExpr is the compiled form of a `[?(<filter>)]` JSONPath predicate.
+// It is evaluated once per candidate Node by match.
+type filterExpr struct {
+	// ors holds groups joined by `||`; each group is a list of terms
+	// joined by `&&`. A candidate matches if any group matches.
+	ors [][]filterTerm
+}
+
+type filterTerm struct {
+	path []string // field path relative to `@`, e.g. @.a.b -> ["a","b"]
+	op   string   // "", "==", "!=", "<", "<=", ">", ">="
+	rhs  filterLit
+}
+
+type filterLit struct {
+	isPath bool
+	path   []string
+	isStr  bool
+	str    string
+	isBool bool
+	bVal   bool
+	num    float64
+}
+
+func compileFilter(s string) (*filterExpr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("jsonpath: empty filter expression")
+	}
+	f := &filterExpr{}
+	for _, orPart := range strings.Split(s, "||") {
+		group := []filterTerm{}
+		for _, andPart := range strings.Split(orPart, "&&") {
+			term, err := compileFilterTerm(strings.TrimSpace(andPart))
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, term)
+		}
+		f.ors = append(f.ors, group)
+	}
+	return f, nil
+}
+
+var filterOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+func compileFilterTerm(s string) (filterTerm, error) {
+	for _, op := range filterOps {
+		if idx := strings.Index(s, op); idx >= 0 {
+			left := strings.TrimSpace(s[:idx])
+			right := strings.TrimSpace(s[idx+len(op):])
+			path, err := parseFilterPath(left)
+			if err != nil {
+				return filterTerm{}, err
+			}
+			rhs, err := parseFilterLit(right)
+			if err != nil {
+				return filterTerm{}, err
+			}
+			return filterTerm{path: path, op: op, rhs: rhs}, nil
+		}
+	}
+	// No operator: plain existence check, e.g. `@.isbn`.
+	path, err := parseFilterPath(s)
+	if err != nil {
+		return filterTerm{}, err
+	}
+	return filterTerm{path: path}, nil
+}
+
+func parseFilterPath(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "@") {
+		return nil, errors.New("jsonpath: filter operand `" + s + "` must start with `@`")
+	}
+	s = s[1:]
+	path := []string{}
+	for len(s) > 0 {
+		switch {
+		case s[0] == '.':
+			s = s[1:]
+		case strings.HasPrefix(s, "['"):
+			end := strings.Index(s, "']")
+			if end < 0 {
+				return nil, errors.New("jsonpath: unterminated `['` in `" + s + "`")
+			}
+			path = append(path, s[2:end])
+			s = s[end+2:]
+			continue
+		default:
+			name, n := readName(s)
+			if name == "" {
+				return nil, errors.New("jsonpath: invalid filter path `" + s + "`")
+			}
+			path = append(path, name)
+			s = s[n:]
+			continue
+		}
+	}
+	return path, nil
+}
+
+func parseFilterLit(s string) (filterLit, error) {
+	switch {
+	case strings.HasPrefix(s, "@"):
+		path, err := parseFilterPath(s)
+		if err != nil {
+			return filterLit{}, err
+		}
+		return filterLit{isPath: true, path: path}, nil
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'"):
+		return filterLit{isStr: true, str: strings.Trim(s, "'")}, nil
+	case strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\""):
+		return filterLit{isStr: true, str: strings.Trim(s, "\"")}, nil
+	case s == "true" || s == "false":
+		return filterLit{isBool: true, bVal: s == "true"}, nil
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return filterLit{}, errors.New("jsonpath: invalid filter literal `" + s + "`")
+		}
+		return filterLit{num: f}, nil
+	}
+}
+
+func (f *filterExpr) match(n Node) bool {
+	for _, group := range f.ors {
+		all := true
+		for _, term := range group {
+			if !term.match(n) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func (t filterTerm) match(n Node) bool {
+	v := resolvePath(n, t.path)
+	if t.op == "" {
+		return v.IsMap() || v.IsArray() || v.IsNum() || v.IsBool() || v.IsString() || v.IsNull()
+	}
+	rhs := t.rhs
+	if rhs.isPath {
+		rv := resolvePath(n, rhs.path)
+		f1, err1 := v.ToFloat64()
+		f2, err2 := rv.ToFloat64()
+		if err1 == nil && err2 == nil {
+			return compareFloat(f1, f2, t.op)
+		}
+		s1, err1 := v.ToString()
+		s2, err2 := rv.ToString()
+		if err1 == nil && err2 == nil {
+			return compareString(s1, s2, t.op)
+		}
+		return false
+	}
+	if rhs.isStr {
+		s, err := v.ToString()
+		if err != nil {
+			return false
+		}
+		return compareString(s, rhs.str, t.op)
+	}
+	if rhs.isBool {
+		b, err := v.ToBool()
+		if err != nil {
+			return false
+		}
+		return (b == rhs.bVal) == (t.op == "==" || t.op == "")
+	}
+	f, err := v.ToFloat64()
+	if err != nil {
+		return false
+	}
+	return compareFloat(f, rhs.num, t.op)
+}
+
+func resolvePath(n Node, path []string) Node {
+	cur := n
+	for _, name := range path {
+		cur = cur.Key(name)
+	}
+	return cur
+}
+
+func compareFloat(a, b float64, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+func compareString(a, b string, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
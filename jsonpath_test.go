@@ -0,0 +1,163 @@
+package jsonhelper
+
+import "testing"
+
+const jsonpathDoc = `{
+	"store": {
+		"book": [
+			{"category": "fiction", "author": "A", "title": "Alpha", "price": 8.95},
+			{"category": "fiction", "author": "B", "title": "Beta", "price": 12.99},
+			{"category": "reference", "author": "C", "title": "Gamma", "price": 22.99},
+			{"category": "fiction", "author": "D", "title": "Delta", "price": 5.99}
+		],
+		"bicycle": {"color": "red", "price": 19.95}
+	}
+}`
+
+func TestPathChildAndIndex(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	v, err := n.Path("$.store.book[0].author").ToString()
+	if err != nil {
+		t.Fatalf("ToString: %v", err)
+	}
+	if v != "A" {
+		t.Fatalf("Path child/index = %q, want %q", v, "A")
+	}
+}
+
+func TestPathNegativeIndex(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	v, err := n.Path("$.store.book[-1].title").ToString()
+	if err != nil {
+		t.Fatalf("ToString: %v", err)
+	}
+	if v != "Delta" {
+		t.Fatalf("Path negative index = %q, want %q", v, "Delta")
+	}
+}
+
+func TestPathWildcard(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	nodes, err := n.Query("$.store.book[*].author")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(nodes) != 4 {
+		t.Fatalf("wildcard matched %d nodes, want 4", len(nodes))
+	}
+}
+
+func TestPathRecursive(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	nodes, err := n.Query("$..price")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(nodes) != 5 {
+		t.Fatalf("recursive matched %d nodes, want 5", len(nodes))
+	}
+}
+
+func TestPathFilter(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	nodes, err := n.Query("$..book[?(@.price<10)].title")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("filter matched %d nodes, want 2", len(nodes))
+	}
+	titles := map[string]bool{}
+	for _, node := range nodes {
+		s, _ := node.ToString()
+		titles[s] = true
+	}
+	if !titles["Alpha"] || !titles["Delta"] {
+		t.Fatalf("filter matched %v, want Alpha and Delta", titles)
+	}
+}
+
+func TestPathUnion(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	nodes, err := n.Query("$.store.book[0,2].title")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("union matched %d nodes, want 2", len(nodes))
+	}
+	s0, _ := nodes[0].ToString()
+	s1, _ := nodes[1].ToString()
+	if s0 != "Alpha" || s1 != "Gamma" {
+		t.Fatalf("union matched %q, %q, want Alpha, Gamma", s0, s1)
+	}
+}
+
+func TestPathSlice(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	nodes, err := n.Query("$.store.book[1:3].title")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("slice matched %d nodes, want 2", len(nodes))
+	}
+	s0, _ := nodes[0].ToString()
+	s1, _ := nodes[1].ToString()
+	if s0 != "Beta" || s1 != "Gamma" {
+		t.Fatalf("slice matched %q, %q, want Beta, Gamma", s0, s1)
+	}
+}
+
+func TestPathNoMatch(t *testing.T) {
+	n, err := Parse([]byte(jsonpathDoc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	node := n.Path("$.store.missing")
+	if _, err := node.ToString(); err == nil {
+		t.Fatalf("expected no-match node to error")
+	}
+}
+
+func TestCompilePathError(t *testing.T) {
+	if _, err := compilePath("$.store["); err == nil {
+		t.Fatalf("expected error for unterminated `[`")
+	}
+	if _, err := compilePath("$.store[abc"); err == nil {
+		t.Fatalf("expected error for unterminated `[`")
+	}
+	if _, err := compilePath("$&store"); err == nil {
+		t.Fatalf("expected error for unexpected character")
+	}
+}
+
+func TestCompileFilterError(t *testing.T) {
+	if _, err := compileFilter(""); err == nil {
+		t.Fatalf("expected error for empty filter expression")
+	}
+	if _, err := compileFilter("price<10"); err == nil {
+		t.Fatalf("expected error for operand not starting with `@`")
+	}
+}
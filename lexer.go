@@ -0,0 +1,169 @@
+package jsonhelper
+
+import (
+	"errors"
+)
+
+// tokenKind enumerates the lexical tokens the lexer produces while scanning
+// a JSON document.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokObjOpen
+	tokObjClose
+	tokArrOpen
+	tokArrClose
+	tokComma
+	tokColon
+	tokString
+	tokNumber
+	tokTrue
+	tokFalse
+	tokNull
+)
+
+// token carries the byte range of its lexeme within the source, so the
+// parser can slice out raw text without copying.
+type token struct {
+	kind  tokenKind
+	start int
+	end   int
+}
+
+// lexer walks a JSON byte slice one token at a time. It intentionally does
+// not build a full token slice up front: the parser pulls tokens as it
+// descends, and skips over nested containers by byte range instead of
+// tokenizing their contents, which is what lets Parse stay lazy.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data}
+}
+
+func (lx *lexer) skipSpace() {
+	for lx.pos < len(lx.data) {
+		switch lx.data[lx.pos] {
+		case ' ', '\t', '\n', '\r':
+			lx.pos++
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next token in the stream.
+func (lx *lexer) next() (token, error) {
+	lx.skipSpace()
+	if lx.pos >= len(lx.data) {
+		return token{kind: tokEOF, start: lx.pos, end: lx.pos}, nil
+	}
+	start := lx.pos
+	switch c := lx.data[lx.pos]; c {
+	case '{':
+		lx.pos++
+		return token{kind: tokObjOpen, start: start, end: lx.pos}, nil
+	case '}':
+		lx.pos++
+		return token{kind: tokObjClose, start: start, end: lx.pos}, nil
+	case '[':
+		lx.pos++
+		return token{kind: tokArrOpen, start: start, end: lx.pos}, nil
+	case ']':
+		lx.pos++
+		return token{kind: tokArrClose, start: start, end: lx.pos}, nil
+	case ',':
+		lx.pos++
+		return token{kind: tokComma, start: start, end: lx.pos}, nil
+	case ':':
+		lx.pos++
+		return token{kind: tokColon, start: start, end: lx.pos}, nil
+	case '"':
+		return lx.lexString()
+	case 't':
+		return lx.lexLiteral("true", tokTrue)
+	case 'f':
+		return lx.lexLiteral("false", tokFalse)
+	case 'n':
+		return lx.lexLiteral("null", tokNull)
+	default:
+		if c == '-' || (c >= '0' && c <= '9') {
+			return lx.lexNumber()
+		}
+		return token{}, errors.New("Invalid JSON format: unexpected character `" + string(c) + "`")
+	}
+}
+
+func (lx *lexer) lexLiteral(lit string, kind tokenKind) (token, error) {
+	start := lx.pos
+	end := start + len(lit)
+	if end > len(lx.data) || string(lx.data[start:end]) != lit {
+		return token{}, errors.New("Invalid JSON format: expected `" + lit + "`")
+	}
+	lx.pos = end
+	return token{kind: kind, start: start, end: end}, nil
+}
+
+func (lx *lexer) lexString() (token, error) {
+	start := lx.pos
+	lx.pos++ // opening quote
+	for lx.pos < len(lx.data) {
+		switch lx.data[lx.pos] {
+		case '\\':
+			lx.pos += 2
+		case '"':
+			lx.pos++
+			return token{kind: tokString, start: start, end: lx.pos}, nil
+		default:
+			lx.pos++
+		}
+	}
+	return token{}, errors.New("Invalid JSON format: unterminated string")
+}
+
+func (lx *lexer) lexNumber() (token, error) {
+	start := lx.pos
+	if lx.data[lx.pos] == '-' {
+		lx.pos++
+	}
+	for lx.pos < len(lx.data) {
+		switch lx.data[lx.pos] {
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '.', 'e', 'E', '+', '-':
+			lx.pos++
+		default:
+			return token{kind: tokNumber, start: start, end: lx.pos}, nil
+		}
+	}
+	return token{kind: tokNumber, start: start, end: lx.pos}, nil
+}
+
+// skipContainer is called right after an open brace/bracket token has been
+// consumed. It scans forward, string- and escape-aware, until the matching
+// close brace/bracket, and returns the offset just past it. It never
+// decodes the contents, so nested containers cost one linear pass rather
+// than a full parse.
+func (lx *lexer) skipContainer(open byte, close byte) (int, error) {
+	depth := 1
+	for lx.pos < len(lx.data) {
+		switch lx.data[lx.pos] {
+		case '"':
+			if _, err := lx.lexString(); err != nil {
+				return 0, err
+			}
+			continue
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				lx.pos++
+				return lx.pos, nil
+			}
+		}
+		lx.pos++
+	}
+	return 0, errors.New("Invalid JSON format: unterminated container")
+}
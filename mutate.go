@@ -0,0 +1,402 @@
+package jsonhelper
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// New creates an empty map Node that can be populated with Set and friends.
+func New() Node {
+	return &nodeMap{v: map[string]interface{}{}, c: map[string]Node{}}
+}
+
+// NewArray creates an empty array Node that can be populated with
+// ArrayAppend and friends.
+func NewArray() Node {
+	return &nodeArray{v: []interface{}{}, c: map[int]Node{}}
+}
+
+func (n *nodeMap) Set(value interface{}, path ...string) (Node, error) {
+	if len(path) == 0 {
+		return nil, errors.New("Set requires at least one path element")
+	}
+	key := path[0]
+	if len(path) == 1 {
+		node, err := createNode(value)
+		if err != nil {
+			return nil, err
+		}
+		n.v[key] = value
+		n.c[key] = node
+		return node, nil
+	}
+	child := n.Key(key)
+	cm, ok := child.(*nodeMap)
+	if !ok {
+		cm = &nodeMap{v: map[string]interface{}{}, c: map[string]Node{}}
+		n.v[key] = cm.v
+		n.c[key] = cm
+	}
+	return cm.Set(value, path[1:]...)
+}
+
+func (n *nodeArray) Set(value interface{}, path ...string) (Node, error) {
+	return nil, errors.New("Node is not map")
+}
+
+func (n *nodeValue) Set(value interface{}, path ...string) (Node, error) {
+	return nil, errors.New("Node is not map")
+}
+
+func (n *nodeError) Set(value interface{}, path ...string) (Node, error) {
+	return nil, n.e
+}
+
+func (n *nodeMap) SetIndex(value interface{}, i int) (Node, error) {
+	return nil, errors.New("Node is not array")
+}
+
+func (n *nodeValue) SetIndex(value interface{}, i int) (Node, error) {
+	return nil, errors.New("Node is not array")
+}
+
+func (n *nodeError) SetIndex(value interface{}, i int) (Node, error) {
+	return nil, n.e
+}
+
+func (n *nodeArray) SetIndex(value interface{}, i int) (Node, error) {
+	if i < 0 || i > len(n.v) {
+		return nil, errors.New("Index `" + strconv.Itoa(i) + "` out of range")
+	}
+	node, err := createNode(value)
+	if err != nil {
+		return nil, err
+	}
+	if i == len(n.v) {
+		n.v = append(n.v, value)
+	} else {
+		n.v[i] = value
+	}
+	n.c[i] = node
+	return node, nil
+}
+
+func (n *nodeMap) Delete(path ...string) error {
+	if len(path) == 0 {
+		return errors.New("Delete requires at least one path element")
+	}
+	key := path[0]
+	if len(path) == 1 {
+		if _, ok := n.v[key]; !ok {
+			return errors.New("Key `" + key + "` not exist")
+		}
+		delete(n.v, key)
+		delete(n.c, key)
+		return nil
+	}
+	child := n.Key(key)
+	cm, ok := child.(*nodeMap)
+	if !ok {
+		return errors.New("Node is not map")
+	}
+	return cm.Delete(path[1:]...)
+}
+
+func (n *nodeArray) Delete(path ...string) error {
+	return errors.New("Node is not map")
+}
+
+func (n *nodeValue) Delete(path ...string) error {
+	return errors.New("Node is not map")
+}
+
+func (n *nodeError) Delete(path ...string) error {
+	return n.e
+}
+
+// materializedArray returns the live element slice backing n.v[key], parsing
+// it from its raw lazy form first if it hasn't been touched yet.
+func (n *nodeMap) materializedArray(key string) ([]interface{}, error) {
+	if arr, ok := n.c[key].(*nodeArray); ok {
+		return arr.v, nil
+	}
+	switch val := n.v[key].(type) {
+	case []interface{}:
+		return val, nil
+	case nil:
+		return []interface{}{}, nil
+	case json.RawMessage:
+		node, err := createNode(val)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := node.(*nodeArray)
+		if !ok {
+			return nil, errors.New("Node is not array")
+		}
+		n.c[key] = arr
+		return arr.v, nil
+	default:
+		return nil, errors.New("Node is not array")
+	}
+}
+
+// materializedArray returns the live element slice backing n.v[i], parsing
+// it from its raw lazy form first if it hasn't been touched yet.
+func (n *nodeArray) materializedArray(i int) ([]interface{}, error) {
+	if i < 0 || i >= len(n.v) {
+		return nil, errors.New("Index `" + strconv.Itoa(i) + "` out of range")
+	}
+	if arr, ok := n.c[i].(*nodeArray); ok {
+		return arr.v, nil
+	}
+	switch val := n.v[i].(type) {
+	case []interface{}:
+		return val, nil
+	case nil:
+		return []interface{}{}, nil
+	case json.RawMessage:
+		node, err := createNode(val)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := node.(*nodeArray)
+		if !ok {
+			return nil, errors.New("Node is not array")
+		}
+		n.c[i] = arr
+		return arr.v, nil
+	default:
+		return nil, errors.New("Node is not array")
+	}
+}
+
+func toInterfaceSlice(value interface{}) ([]interface{}, error) {
+	if s, ok := value.([]interface{}); ok {
+		return s, nil
+	}
+	r := reflect.ValueOf(value)
+	if r.Kind() != reflect.Array && r.Kind() != reflect.Slice {
+		return nil, errors.New("value is not an array")
+	}
+	result := make([]interface{}, r.Len())
+	for i := range result {
+		result[i] = r.Index(i).Interface()
+	}
+	return result, nil
+}
+
+// appendAt descends path, treating each segment as a map key unless the
+// node at that point is an array, in which case the segment is parsed as
+// an index. The final segment names the array to extend; it is created on
+// a nodeMap if missing, matching Set's auto-vivification of intermediate
+// maps.
+func appendAt(n Node, elems []interface{}, path []string) error {
+	if len(path) == 0 {
+		return errors.New("Node is not array")
+	}
+	if err := validateElems(elems); err != nil {
+		return err
+	}
+	switch cur := n.(type) {
+	case *nodeMap:
+		key := path[0]
+		if len(path) == 1 {
+			arr, err := cur.materializedArray(key)
+			if err != nil {
+				return err
+			}
+			cur.v[key] = append(arr, elems...)
+			delete(cur.c, key)
+			return nil
+		}
+		return appendAt(cur.Key(key), elems, path[1:])
+	case *nodeArray:
+		idx, err := strconv.Atoi(path[0])
+		if err != nil {
+			return errors.New("jsonhelper: invalid array index `" + path[0] + "`")
+		}
+		if len(path) == 1 {
+			arr, err := cur.materializedArray(idx)
+			if err != nil {
+				return err
+			}
+			cur.v[idx] = append(arr, elems...)
+			delete(cur.c, idx)
+			return nil
+		}
+		return appendAt(cur.Index(idx), elems, path[1:])
+	case *nodeError:
+		return cur.e
+	default:
+		return errors.New("Node is not map")
+	}
+}
+
+// validateElems confirms every element can become a Node before any of
+// them are written through into a backing v slice, the same way SetIndex
+// validates a single value before writing it.
+func validateElems(elems []interface{}) error {
+	for _, elem := range elems {
+		if _, err := createNode(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nodeMap) ArrayAppend(value interface{}, path ...string) error {
+	return appendAt(n, []interface{}{value}, path)
+}
+
+func (n *nodeArray) ArrayAppend(value interface{}, path ...string) error {
+	if len(path) == 0 {
+		if err := validateElems([]interface{}{value}); err != nil {
+			return err
+		}
+		n.v = append(n.v, value)
+		return nil
+	}
+	return appendAt(n, []interface{}{value}, path)
+}
+
+func (n *nodeValue) ArrayAppend(value interface{}, path ...string) error {
+	return errors.New("Node is not array")
+}
+
+func (n *nodeError) ArrayAppend(value interface{}, path ...string) error {
+	return n.e
+}
+
+func (n *nodeMap) ArrayConcat(value interface{}, path ...string) error {
+	elems, err := toInterfaceSlice(value)
+	if err != nil {
+		return err
+	}
+	return appendAt(n, elems, path)
+}
+
+func (n *nodeArray) ArrayConcat(value interface{}, path ...string) error {
+	elems, err := toInterfaceSlice(value)
+	if err != nil {
+		return err
+	}
+	if len(path) != 0 {
+		return appendAt(n, elems, path)
+	}
+	if err := validateElems(elems); err != nil {
+		return err
+	}
+	n.v = append(n.v, elems...)
+	return nil
+}
+
+func (n *nodeValue) ArrayConcat(value interface{}, path ...string) error {
+	return errors.New("Node is not array")
+}
+
+func (n *nodeError) ArrayConcat(value interface{}, path ...string) error {
+	return n.e
+}
+
+func (n *nodeMap) Merge(other Node) error {
+	if !other.IsMap() {
+		return errors.New("Node is not map")
+	}
+	om, err := other.ToMap()
+	if err != nil {
+		return err
+	}
+	for k, v := range om {
+		if v.IsMap() {
+			if em, ok := n.Key(k).(*nodeMap); ok {
+				if err := em.Merge(v); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		raw, err := nodeToRaw(v)
+		if err != nil {
+			return err
+		}
+		if _, err := n.Set(raw, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *nodeArray) Merge(other Node) error {
+	return errors.New("Node is not map")
+}
+
+func (n *nodeValue) Merge(other Node) error {
+	return errors.New("Node is not map")
+}
+
+func (n *nodeError) Merge(other Node) error {
+	return n.e
+}
+
+// nodeToRaw converts a Node (and its already-mutated children) back into a
+// plain interface{} tree suitable for json.Marshal.
+func nodeToRaw(n Node) (interface{}, error) {
+	switch v := n.(type) {
+	case *nodeMap:
+		result := map[string]interface{}{}
+		for k := range v.v {
+			raw, err := nodeToRaw(v.Key(k))
+			if err != nil {
+				return nil, err
+			}
+			result[k] = raw
+		}
+		return result, nil
+	case *nodeArray:
+		result := make([]interface{}, len(v.v))
+		for i := range v.v {
+			raw, err := nodeToRaw(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = raw
+		}
+		return result, nil
+	case *nodeValue:
+		return v.v, nil
+	case *nodeError:
+		return nil, v.e
+	}
+	return nil, errors.New("Invalid node type")
+}
+
+func marshalNode(n Node) ([]byte, error) {
+	raw, err := nodeToRaw(n)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(raw)
+}
+
+func (n *nodeMap) Bytes() ([]byte, error) { return marshalNode(n) }
+func (n *nodeMap) String() string         { return nodeString(n) }
+
+func (n *nodeArray) Bytes() ([]byte, error) { return marshalNode(n) }
+func (n *nodeArray) String() string         { return nodeString(n) }
+
+func (n *nodeValue) Bytes() ([]byte, error) { return marshalNode(n) }
+func (n *nodeValue) String() string         { return nodeString(n) }
+
+func (n *nodeError) Bytes() ([]byte, error) { return nil, n.e }
+func (n *nodeError) String() string         { return "" }
+
+func nodeString(n Node) string {
+	b, err := marshalNode(n)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,164 @@
+package jsonhelper
+
+import "testing"
+
+func TestSetAndDelete(t *testing.T) {
+	n := New()
+	if _, err := n.Set(1, "a", "b"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	v, err := n.Key("a").Key("b").ToInt()
+	if err != nil || v != 1 {
+		t.Fatalf("Key(a).Key(b) = %v, %v, want 1, nil", v, err)
+	}
+	if err := n.Delete("a", "b"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := n.Delete("a", "b"); err == nil {
+		t.Fatalf("expected error deleting an already-deleted key")
+	}
+}
+
+func TestSetIntoArrayValuedKey(t *testing.T) {
+	n, err := Parse([]byte(`{"a":[1,2,3]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Set replaces the array wholesale with a map; this mirrors Set's
+	// existing "overwrite whatever was there" behavior for scalars.
+	if _, err := n.Set(map[string]interface{}{"x": 1}, "a", "y"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	v, err := n.Key("a").Key("y").Key("x").ToInt()
+	if err != nil || v != 1 {
+		t.Fatalf("Key(a).Key(y).Key(x) = %v, %v, want 1, nil", v, err)
+	}
+}
+
+func TestSetIndexPastEnd(t *testing.T) {
+	n := NewArray()
+	if _, err := n.SetIndex("x", 0); err != nil {
+		t.Fatalf("SetIndex(0): %v", err)
+	}
+	if _, err := n.SetIndex("y", 5); err == nil {
+		t.Fatalf("expected out-of-range error setting index 5 on a length-1 array")
+	}
+	if _, err := n.SetIndex("z", 1); err != nil {
+		t.Fatalf("SetIndex(1) (append position): %v", err)
+	}
+	if n.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", n.Len())
+	}
+}
+
+func TestArrayConcatOnLazyArray(t *testing.T) {
+	n, err := Parse([]byte(`{"a":[1,2]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// "a" is still an unmaterialized json.RawMessage at this point.
+	if err := n.ArrayConcat([]interface{}{3, 4}, "a"); err != nil {
+		t.Fatalf("ArrayConcat: %v", err)
+	}
+	if n.Key("a").Len() != 4 {
+		t.Fatalf("Key(a).Len() = %d, want 4", n.Key("a").Len())
+	}
+	v, err := n.Key("a").Index(3).ToInt()
+	if err != nil || v != 4 {
+		t.Fatalf("Key(a).Index(3) = %v, %v, want 4, nil", v, err)
+	}
+}
+
+func TestArrayAppendOnNodeArray(t *testing.T) {
+	n := NewArray()
+	if err := n.ArrayAppend(1); err != nil {
+		t.Fatalf("ArrayAppend: %v", err)
+	}
+	if err := n.ArrayAppend(2); err != nil {
+		t.Fatalf("ArrayAppend: %v", err)
+	}
+	if n.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", n.Len())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, err := Parse([]byte(`{"x":1,"nested":{"p":1,"q":2}}`))
+	if err != nil {
+		t.Fatalf("Parse a: %v", err)
+	}
+	b, err := Parse([]byte(`{"y":2,"nested":{"q":20,"r":3}}`))
+	if err != nil {
+		t.Fatalf("Parse b: %v", err)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if v, err := a.Key("x").ToInt(); err != nil || v != 1 {
+		t.Fatalf("x = %v, %v, want 1, nil", v, err)
+	}
+	if v, err := a.Key("y").ToInt(); err != nil || v != 2 {
+		t.Fatalf("y = %v, %v, want 2, nil", v, err)
+	}
+	if v, err := a.Key("nested").Key("p").ToInt(); err != nil || v != 1 {
+		t.Fatalf("nested.p = %v, %v, want 1, nil", v, err)
+	}
+	if v, err := a.Key("nested").Key("q").ToInt(); err != nil || v != 20 {
+		t.Fatalf("nested.q = %v, %v, want 20, nil", v, err)
+	}
+	if v, err := a.Key("nested").Key("r").ToInt(); err != nil || v != 3 {
+		t.Fatalf("nested.r = %v, %v, want 3, nil", v, err)
+	}
+}
+
+func TestMergeNotMap(t *testing.T) {
+	a := New()
+	b := NewArray()
+	if err := a.Merge(b); err == nil {
+		t.Fatalf("expected error merging an array Node into a map")
+	}
+}
+
+func TestBytesRoundTripsNull(t *testing.T) {
+	n, err := Parse([]byte(`{"a":null,"b":2}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !n.Key("a").IsNull() {
+		t.Fatalf("Key(a): expected IsNull() to be true")
+	}
+	b, err := n.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if string(b) != `{"a":null,"b":2}` {
+		t.Fatalf("Bytes() = %q, want %q", b, `{"a":null,"b":2}`)
+	}
+}
+
+func TestSetNilIsValidated(t *testing.T) {
+	n := New()
+	if _, err := n.Set(nil, "a"); err != nil {
+		t.Fatalf("Set(nil): %v", err)
+	}
+	if !n.Key("a").IsNull() {
+		t.Fatalf("Key(a): expected IsNull() to be true")
+	}
+	if _, err := n.Bytes(); err != nil {
+		t.Fatalf("Bytes after Set(nil): %v", err)
+	}
+}
+
+func TestArrayAppendNilIsValidated(t *testing.T) {
+	n := NewArray()
+	if err := n.ArrayAppend(nil); err != nil {
+		t.Fatalf("ArrayAppend(nil): %v", err)
+	}
+	b, err := n.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes after ArrayAppend(nil): %v", err)
+	}
+	if string(b) != `[null]` {
+		t.Fatalf("Bytes() = %q, want %q", b, `[null]`)
+	}
+}
@@ -0,0 +1,127 @@
+package jsonhelper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// numberString returns the original textual form of a numeric nodeValue.
+// Values produced by Parse are json.Number, which preserves every digit of
+// the source text; values produced by Set/New carry a plain Go numeric
+// type and are formatted on the fly.
+func numberString(v interface{}) string {
+	if num, ok := v.(json.Number); ok {
+		return string(num)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func (n *nodeValue) Raw() string {
+	switch n.t {
+	case tNum:
+		return numberString(n.v)
+	case tString:
+		return n.v.(string)
+	case tBool:
+		if n.v.(bool) {
+			return "true"
+		}
+		return "false"
+	case tNull:
+		return "null"
+	}
+	return ""
+}
+
+// IsInt reports whether the number's textual form is a whole number, i.e.
+// has no fractional part or exponent.
+func (n *nodeValue) IsInt() bool {
+	if !n.IsNum() {
+		return false
+	}
+	_, ok := new(big.Int).SetString(n.Raw(), 10)
+	return ok
+}
+
+func (n *nodeValue) IsFloat() bool {
+	return n.IsNum() && !n.IsInt()
+}
+
+func (n *nodeValue) ToBigInt() (*big.Int, error) {
+	if !n.IsNum() {
+		return nil, errors.New("Node is not number")
+	}
+	s := n.Raw()
+	if bi, ok := new(big.Int).SetString(s, 10); ok {
+		return bi, nil
+	}
+	bf, _, err := big.ParseFloat(s, 10, 200, big.ToNearestEven)
+	if err != nil {
+		return nil, errors.New("Node value `" + s + "` is not a valid number")
+	}
+	bi, acc := bf.Int(nil)
+	if acc != big.Exact {
+		return nil, errors.New("Node value `" + s + "` is not a whole number")
+	}
+	return bi, nil
+}
+
+func (n *nodeValue) ToBigFloat() (*big.Float, error) {
+	if !n.IsNum() {
+		return nil, errors.New("Node is not number")
+	}
+	s := n.Raw()
+	bf, _, err := big.ParseFloat(s, 10, 200, big.ToNearestEven)
+	if err != nil {
+		return nil, errors.New("Node value `" + s + "` is not a valid number")
+	}
+	return bf, nil
+}
+
+func (n *nodeValue) ToInt64() (int64, error) {
+	bi, err := n.ToBigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !bi.IsInt64() {
+		return 0, errors.New("Node value `" + n.Raw() + "` overflows int64")
+	}
+	return bi.Int64(), nil
+}
+
+func (n *nodeValue) ToUint64() (uint64, error) {
+	bi, err := n.ToBigInt()
+	if err != nil {
+		return 0, err
+	}
+	if bi.Sign() < 0 || !bi.IsUint64() {
+		return 0, errors.New("Node value `" + n.Raw() + "` overflows uint64")
+	}
+	return bi.Uint64(), nil
+}
+
+func (n *nodeValue) ToInt() (int, error) {
+	i64, err := n.ToInt64()
+	if err != nil {
+		return 0, err
+	}
+	iv := int(i64)
+	if int64(iv) != i64 {
+		return 0, errors.New("Node value `" + n.Raw() + "` overflows int")
+	}
+	return iv, nil
+}
+
+func (n *nodeValue) ToFloat64() (float64, error) {
+	if !n.IsNum() {
+		return 0, errors.New("Node is not number")
+	}
+	f, err := strconv.ParseFloat(n.Raw(), 64)
+	if err != nil {
+		return 0, err
+	}
+	return f, nil
+}
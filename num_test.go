@@ -0,0 +1,38 @@
+package jsonhelper
+
+import "testing"
+
+func TestNumberPrecision(t *testing.T) {
+	n, err := Parse([]byte(`{"id":9007199254740993,"big":1e400,"price":19.99}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	id := n.Key("id")
+	if !id.IsInt() {
+		t.Fatalf("id: expected IsInt() to be true")
+	}
+	i64, err := id.ToInt64()
+	if err != nil {
+		t.Fatalf("id.ToInt64: %v", err)
+	}
+	if i64 != 9007199254740993 {
+		t.Fatalf("id.ToInt64 = %d, want 9007199254740993 (lost precision)", i64)
+	}
+
+	big := n.Key("big")
+	if _, err := big.ToFloat64(); err == nil {
+		t.Fatalf("big.ToFloat64: expected an overflow error for 1e400")
+	}
+
+	price := n.Key("price")
+	if !price.IsFloat() {
+		t.Fatalf("price: expected IsFloat() to be true")
+	}
+	if price.Raw() != "19.99" {
+		t.Fatalf("price.Raw() = %q, want %q", price.Raw(), "19.99")
+	}
+	if _, err := price.ToInt64(); err == nil {
+		t.Fatalf("price.ToInt64: expected an error for a fractional value")
+	}
+}
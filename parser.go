@@ -0,0 +1,219 @@
+package jsonhelper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ParseReader parses JSON read from r the same way Parse does, without
+// requiring the caller to buffer it first.
+func ParseReader(r io.Reader) (Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// parseContainer parses the top-level value and constructs its Node.
+// Nested containers found along the way are kept as raw byte slices
+// (json.RawMessage) and are only parsed the first time Key/Index visits
+// them, so touching a handful of fields in a large document never pays
+// for the whole tree.
+func parseContainer(data []byte) (Node, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("Invalid JSON format")
+	}
+	switch data[0] {
+	case '{':
+		return parseObject(data)
+	case '[':
+		return parseArray(data)
+	default:
+		return nil, errors.New("Invalid JSON format")
+	}
+}
+
+func parseObject(data []byte) (*nodeMap, error) {
+	lx := newLexer(data)
+	tok, err := lx.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokObjOpen {
+		return nil, errors.New("Invalid JSON format: expected `{`")
+	}
+	v := map[string]interface{}{}
+	n := &nodeMap{v: v, c: map[string]Node{}}
+
+	tok, err = lx.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokObjClose {
+		if err := checkTrailing(lx); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	for {
+		if tok.kind != tokString {
+			return nil, errors.New("Invalid JSON format: expected object key")
+		}
+		key, err := decodeString(data[tok.start:tok.end])
+		if err != nil {
+			return nil, err
+		}
+		colon, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if colon.kind != tokColon {
+			return nil, errors.New("Invalid JSON format: expected `:`")
+		}
+		val, err := scanValue(lx, data)
+		if err != nil {
+			return nil, err
+		}
+		v[key] = val
+
+		tok, err = lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokComma {
+			tok, err = lx.next()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if tok.kind == tokObjClose {
+			if err := checkTrailing(lx); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+		return nil, errors.New("Invalid JSON format: expected `,` or `}`")
+	}
+}
+
+func parseArray(data []byte) (*nodeArray, error) {
+	lx := newLexer(data)
+	tok, err := lx.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokArrOpen {
+		return nil, errors.New("Invalid JSON format: expected `[`")
+	}
+	v := []interface{}{}
+	n := &nodeArray{v: v, c: map[int]Node{}}
+
+	tok, err = lx.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokArrClose {
+		if err := checkTrailing(lx); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	for {
+		val, err := scanValueFromToken(lx, data, tok)
+		if err != nil {
+			return nil, err
+		}
+		n.v = append(n.v, val)
+
+		tok, err = lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokComma {
+			tok, err = lx.next()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if tok.kind == tokArrClose {
+			if err := checkTrailing(lx); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+		return nil, errors.New("Invalid JSON format: expected `,` or `]`")
+	}
+}
+
+// checkTrailing reports an error if anything other than whitespace follows
+// the container lx just finished reading, matching encoding/json.Unmarshal,
+// which rejects trailing non-whitespace instead of silently discarding it.
+func checkTrailing(lx *lexer) error {
+	tok, err := lx.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokEOF {
+		return errors.New("Invalid JSON format: unexpected data after top-level value")
+	}
+	return nil
+}
+
+// scanValue reads the next value from lx. Scalars are decoded immediately;
+// objects and arrays are captured as a raw json.RawMessage byte range so
+// their own parse is deferred until something actually accesses them.
+func scanValue(lx *lexer, data []byte) (interface{}, error) {
+	tok, err := lx.next()
+	if err != nil {
+		return nil, err
+	}
+	return scanValueFromToken(lx, data, tok)
+}
+
+func scanValueFromToken(lx *lexer, data []byte, tok token) (interface{}, error) {
+	switch tok.kind {
+	case tokString:
+		return decodeString(data[tok.start:tok.end])
+	case tokNumber:
+		return json.Number(data[tok.start:tok.end]), nil
+	case tokTrue:
+		return true, nil
+	case tokFalse:
+		return false, nil
+	case tokNull:
+		return nil, nil
+	case tokObjOpen:
+		end, err := lx.skipContainer('{', '}')
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(data[tok.start:end]), nil
+	case tokArrOpen:
+		end, err := lx.skipContainer('[', ']')
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(data[tok.start:end]), nil
+	default:
+		return nil, errors.New("Invalid JSON format: unexpected token")
+	}
+}
+
+// decodeString unescapes a JSON string literal, raw including its
+// surrounding quotes.
+func decodeString(raw []byte) (string, error) {
+	if !bytes.ContainsRune(raw, '\\') {
+		return string(raw[1 : len(raw)-1]), nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
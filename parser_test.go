@@ -0,0 +1,95 @@
+package jsonhelper
+
+import "testing"
+
+func TestParseNestedObjectsAndArrays(t *testing.T) {
+	n, err := Parse([]byte(`{"a":{"b":[1,2,{"c":3}]},"d":[]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	v, err := n.Path("$.a.b[2].c").ToInt()
+	if err != nil {
+		t.Fatalf("ToInt: %v", err)
+	}
+	if v != 3 {
+		t.Fatalf("nested value = %d, want 3", v)
+	}
+	if n.Key("d").Len() != 0 {
+		t.Fatalf("empty array Len() = %d, want 0", n.Key("d").Len())
+	}
+}
+
+func TestParseStringEscapesAndUnicode(t *testing.T) {
+	n, err := Parse([]byte(`{"s":"a\tb\n\"c\"é😀"}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	s, err := n.Key("s").ToString()
+	if err != nil {
+		t.Fatalf("ToString: %v", err)
+	}
+	want := "a\tb\n\"c\"é\U0001F600"
+	if s != want {
+		t.Fatalf("ToString() = %q, want %q", s, want)
+	}
+}
+
+func TestParseMalformedInput(t *testing.T) {
+	cases := []string{
+		``,
+		`{`,
+		`{"a":}`,
+		`{"a":1,}`,
+		`[1,2`,
+		`{"a" 1}`,
+		`tru`,
+		`"unterminated`,
+		`nul`,
+		`{"a":1}garbage`,
+		`{"a":1}{"b":2}`,
+		`[1,2]trailing`,
+	}
+	for _, c := range cases {
+		if _, err := Parse([]byte(c)); err == nil {
+			t.Errorf("Parse(%q): expected error, got none", c)
+		}
+	}
+}
+
+func TestParseAcceptsTrailingWhitespace(t *testing.T) {
+	// Trailing whitespace after the top-level value is not "trailing
+	// data" and must still parse, matching encoding/json.Unmarshal.
+	if _, err := Parse([]byte("{\"a\":1}  \n")); err != nil {
+		t.Fatalf("Parse with trailing whitespace: %v", err)
+	}
+}
+
+func TestParseLazyDeferralAndMutation(t *testing.T) {
+	n, err := Parse([]byte(`{"a":{"x":1,"y":2},"b":[1,2,3]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	// Touching "a" triggers its lazy json.RawMessage parse; deleting a key
+	// from it must be visible on a re-fetch through the same parent node.
+	if err := n.Key("a").Delete("x"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n.Key("a").Key("x").IsNum() {
+		t.Fatalf("Key(x) still present after Delete")
+	}
+	if y, err := n.Key("a").Key("y").ToInt(); err != nil || y != 2 {
+		t.Fatalf("Key(y) = %v, %v, want 2, nil", y, err)
+	}
+
+	// "b" is untouched raw bytes until ArrayAppend materializes it.
+	if err := n.ArrayAppend(4, "b"); err != nil {
+		t.Fatalf("ArrayAppend: %v", err)
+	}
+	if n.Key("b").Len() != 4 {
+		t.Fatalf("Key(b).Len() = %d, want 4", n.Key("b").Len())
+	}
+	if v, err := n.Key("b").Index(3).ToInt(); err != nil || v != 4 {
+		t.Fatalf("Key(b).Index(3) = %v, %v, want 4, nil", v, err)
+	}
+}